@@ -4,8 +4,10 @@
 package backoff
 
 import (
+	"context"
 	"errors"
 	"fmt"
+	"io"
 	"math"
 	"math/rand"
 	"reflect"
@@ -24,6 +26,10 @@ const (
 var (
 	// ErrInvalidRetriesNumber is an error returned when the number of retries is invalid
 	ErrInvalidRetriesNumber = errors.New("invalid number of retries")
+
+	// ErrElapsedTimeExceeded is an error returned when the total time spent retrying
+	// has exceeded the budget set via MaxElapsedTime
+	ErrElapsedTimeExceeded = errors.New("max elapsed time exceeded")
 )
 
 // UnrecoverableError is a type that wraps an error
@@ -38,15 +44,32 @@ func NewUnrecoverableError(err error) *UnrecoverableError {
 }
 
 type backoffConfig struct {
-	backoffFunc  Func
-	callbackFunc CallbackFunc
-	maxRetries   uint
-	retryAfter   uint
-	jitterFunc   JitterFunc
-	exponential  bool
-	label        string
-	log          *log.Logger
-	timeScale    time.Duration
+	backoffFunc    Func
+	backoffCtxFunc FuncCtx
+	callbackFunc   CallbackFunc
+	maxRetries     uint
+	retryAfter     uint
+	jitterFunc     JitterFunc
+	exponential    bool
+	label          string
+	log            *log.Logger
+	timeScale      time.Duration
+	ctx            context.Context
+
+	maxElapsedTime    time.Duration
+	perAttemptTimeout time.Duration
+
+	strategy   Strategy
+	baseDelay  time.Duration
+	maxDelay   time.Duration
+	multiplier float64
+
+	retryIf RetryIfFunc
+	abortIf RetryIfFunc
+	onRetry OnRetryFunc
+
+	circuitBreaker *CircuitBreaker
+	bulkhead       chan struct{}
 
 	invocations       uint
 	failedInvocations uint
@@ -55,6 +78,11 @@ type backoffConfig struct {
 // Func is the function type being wrapped by the backoff function, returning a result and an error.
 type Func func() (result interface{}, err error)
 
+// FuncCtx is the function type being wrapped by the context-aware backoff functions
+// (LinearCtx, ExponentialCtx). It receives the context passed to those functions, so
+// the wrapped function can also be cancelled mid-flight.
+type FuncCtx func(ctx context.Context) (result interface{}, err error)
+
 // CallbackFunc is the function type to be used as a callback on backoff success
 type CallbackFunc func(b *backoffConfig, r interface{})
 
@@ -65,6 +93,26 @@ type ConfigFunc func(b *backoffConfig) error
 // value with added jitter
 type JitterFunc func(cap uint) int
 
+// Strategy computes the delay to wait before a retry attempt. attempt is the 1-based
+// number of the attempt about to be made, and prev is the delay that was used before
+// the previous attempt (zero before the first attempt). Unlike JitterFunc, a Strategy
+// can depend on the previous delay, which the decorrelated jitter backoff shape needs in
+// order to grow or shrink relative to the last delay rather than purely as a function of
+// the attempt number. The capped exponential shape uses the attempt number only.
+type Strategy interface {
+	NextDelay(attempt uint, prev time.Duration) time.Duration
+}
+
+// RetryIfFunc is a predicate consulted after a failed attempt. attempt is the 1-based
+// number of the attempt that just failed. Returning false aborts the retry sequence
+// immediately, as if err had been wrapped in UnrecoverableError.
+type RetryIfFunc func(err error, attempt uint) bool
+
+// OnRetryFunc is called after every failed attempt, including ones that go on to abort
+// the retry sequence. Unlike CallbackFunc, which only fires on success, OnRetryFunc lets
+// callers observe every failure for metrics or tracing purposes.
+type OnRetryFunc func(attempt uint, err error)
+
 // Linear execute the function f repeatedly, until its result is non-nil and no error is returned.
 // It keeps the time between each iteration constant.
 //
@@ -82,6 +130,7 @@ func Linear(f Func, opts ...ConfigFunc) (interface{}, error) {
 		label:        label,
 		timeScale:    time.Millisecond,
 		log:          log.New(),
+		ctx:          context.Background(),
 	}
 
 	for _, opt := range opts {
@@ -106,10 +155,12 @@ func MustLinear(f Func, opts ...ConfigFunc) interface{} {
 		callbackFunc: nil,
 		maxRetries:   10,
 		retryAfter:   500,
+		jitterFunc:   FullJitter,
 		exponential:  false,
 		label:        label,
 		timeScale:    time.Millisecond,
 		log:          log.New(),
+		ctx:          context.Background(),
 	}
 
 	for _, opt := range opts {
@@ -134,10 +185,12 @@ func Exponential(f Func, opts ...ConfigFunc) (interface{}, error) {
 		callbackFunc: nil,
 		maxRetries:   10,
 		retryAfter:   500,
+		jitterFunc:   FullJitter,
 		exponential:  true,
 		label:        label,
 		timeScale:    time.Millisecond,
 		log:          log.New(),
+		ctx:          context.Background(),
 	}
 
 	for _, opt := range opts {
@@ -162,10 +215,12 @@ func MustExponential(f Func, opts ...ConfigFunc) interface{} {
 		callbackFunc: nil,
 		maxRetries:   10,
 		retryAfter:   500,
+		jitterFunc:   FullJitter,
 		exponential:  true,
 		label:        label,
 		timeScale:    time.Millisecond,
 		log:          log.New(),
+		ctx:          context.Background(),
 	}
 
 	for _, opt := range opts {
@@ -178,6 +233,125 @@ func MustExponential(f Func, opts ...ConfigFunc) interface{} {
 	return mustExec(f, cfg)
 }
 
+// LinearT is a generics-based variant of Linear that returns T directly instead of
+// interface{}, so callers no longer need to type-assert the result. It shares Linear's
+// exec loop internally by adapting f to Func.
+func LinearT[T any](f func() (T, error), opts ...ConfigFunc) (T, error) {
+	label := getLabel(f)
+	result, err := Linear(adaptFuncT(f), append([]ConfigFunc{Label(label)}, opts...)...)
+	t, _ := result.(T)
+	return t, err
+}
+
+// MustLinearT is a generics-based variant of MustLinear that returns T directly instead
+// of interface{}.
+func MustLinearT[T any](f func() (T, error), opts ...ConfigFunc) T {
+	label := getLabel(f)
+	result := MustLinear(adaptFuncT(f), append([]ConfigFunc{Label(label)}, opts...)...)
+	return result.(T)
+}
+
+// ExponentialT is a generics-based variant of Exponential that returns T directly
+// instead of interface{}, so callers no longer need to type-assert the result. It shares
+// Exponential's exec loop internally by adapting f to Func.
+func ExponentialT[T any](f func() (T, error), opts ...ConfigFunc) (T, error) {
+	label := getLabel(f)
+	result, err := Exponential(adaptFuncT(f), append([]ConfigFunc{Label(label)}, opts...)...)
+	t, _ := result.(T)
+	return t, err
+}
+
+// MustExponentialT is a generics-based variant of MustExponential that returns T
+// directly instead of interface{}.
+func MustExponentialT[T any](f func() (T, error), opts ...ConfigFunc) T {
+	label := getLabel(f)
+	result := MustExponential(adaptFuncT(f), append([]ConfigFunc{Label(label)}, opts...)...)
+	return result.(T)
+}
+
+// adaptFuncT adapts a typed func() (T, error), as used by the generics-based *T
+// functions, to the untyped Func signature that exec/mustExec operate on.
+func adaptFuncT[T any](f func() (T, error)) Func {
+	return func() (interface{}, error) { return f() }
+}
+
+// CallbackT is a configuration option that sets a typed callback for the generics-based
+// LinearT/ExponentialT/MustLinearT/MustExponentialT functions, analogous to Callback.
+func CallbackT[T any](f func(b *backoffConfig, r T)) ConfigFunc {
+	return func(b *backoffConfig) error {
+		b.callbackFunc = func(cfg *backoffConfig, r interface{}) {
+			f(cfg, r.(T))
+		}
+		return nil
+	}
+}
+
+// LinearCtx execute the function f repeatedly, until its result is non-nil and no error is
+// returned, the context is done, or the maximum allowed number of retries has been reached.
+// It keeps the time between each iteration constant.
+//
+// Unlike Linear, the wait between retries is interruptible: if ctx is cancelled or its
+// deadline is exceeded, LinearCtx stops retrying immediately and returns the last observed
+// error (or ctx.Err() if no attempt has completed yet). ctx is also passed to f on every
+// attempt, so f can abort mid-flight.
+func LinearCtx(ctx context.Context, f FuncCtx, opts ...ConfigFunc) (interface{}, error) {
+	label := getLabel(f)
+	cfg := &backoffConfig{
+		backoffCtxFunc: f,
+		callbackFunc:   nil,
+		maxRetries:     10,
+		retryAfter:     500,
+		jitterFunc:     FullJitter,
+		exponential:    false,
+		label:          label,
+		timeScale:      time.Millisecond,
+		log:            log.New(),
+		ctx:            ctx,
+	}
+
+	for _, opt := range opts {
+		err := opt(cfg)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return exec(nil, cfg)
+}
+
+// ExponentialCtx execute the function f repeatedly, until its result is non-nil and no error is
+// returned, the context is done, or the maximum allowed number of retries has been reached.
+// It increases the time between retries after each iteration.
+//
+// Unlike Exponential, the wait between retries is interruptible: if ctx is cancelled or its
+// deadline is exceeded, ExponentialCtx stops retrying immediately and returns the last observed
+// error (or ctx.Err() if no attempt has completed yet). ctx is also passed to f on every
+// attempt, so f can abort mid-flight.
+func ExponentialCtx(ctx context.Context, f FuncCtx, opts ...ConfigFunc) (interface{}, error) {
+	label := getLabel(f)
+	cfg := &backoffConfig{
+		backoffCtxFunc: f,
+		callbackFunc:   nil,
+		maxRetries:     10,
+		retryAfter:     500,
+		jitterFunc:     FullJitter,
+		exponential:    true,
+		label:          label,
+		timeScale:      time.Millisecond,
+		log:            log.New(),
+		ctx:            ctx,
+	}
+
+	for _, opt := range opts {
+		err := opt(cfg)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return exec(nil, cfg)
+}
+
 // Retries is a configuration option that sets the number of retries to attempt before giving up.
 func Retries(n uint) ConfigFunc {
 	return func(b *backoffConfig) error {
@@ -206,7 +380,7 @@ func Label(label string) ConfigFunc {
 // The configuration option `TimeScale` can be used to change the duration unit.
 func RetryAfter(n uint) ConfigFunc {
 	return func(b *backoffConfig) error {
-		b.maxRetries = n
+		b.retryAfter = n
 		return nil
 	}
 }
@@ -220,10 +394,46 @@ func TimeScale(t time.Duration) ConfigFunc {
 	}
 }
 
-// Logger is a configuration option that sets the destination of logging. Practically it expects an io.Writer for destination
-func Logger(dest *log.Logger) ConfigFunc {
+// Context is a configuration option that sets the context.Context used to cancel a retry
+// sequence early. When ctx is done, exec stops waiting/retrying and returns immediately
+// with the last observed error (or ctx.Err() if no attempt has completed yet). It defaults
+// to context.Background(), i.e. no cancellation.
+func Context(ctx context.Context) ConfigFunc {
 	return func(b *backoffConfig) error {
-		b.log = dest
+		b.ctx = ctx
+		return nil
+	}
+}
+
+// MaxElapsedTime is a configuration option that bounds the total wall-clock time spent
+// retrying. Once the time elapsed since the first attempt would exceed d, exec stops
+// retrying and returns the last observed error wrapped with ErrElapsedTimeExceeded,
+// regardless of how many retries remain.
+func MaxElapsedTime(d time.Duration) ConfigFunc {
+	return func(b *backoffConfig) error {
+		b.maxElapsedTime = d
+		return nil
+	}
+}
+
+// PerAttemptTimeout is a configuration option that bounds how long a single invocation of
+// the wrapped function is allowed to run. For a plain Func that has not returned within
+// d, the attempt is abandoned (the function keeps running) and treated as failed with an
+// error wrapping context.DeadlineExceeded, and the backoff loop proceeds to the next
+// retry. For the Ctx variants, the function receives a context that expires after d, and
+// the attempt's outcome is whatever the function returns; a function that ignores its
+// context is not interrupted.
+func PerAttemptTimeout(d time.Duration) ConfigFunc {
+	return func(b *backoffConfig) error {
+		b.perAttemptTimeout = d
+		return nil
+	}
+}
+
+// Log is a configuration option that sets the destination of logging. Practically it expects an io.Writer for destination
+func Log(dest io.Writer) ConfigFunc {
+	return func(b *backoffConfig) error {
+		b.log.SetOutput(dest)
 		return nil
 	}
 }
@@ -237,6 +447,39 @@ func Callback(f CallbackFunc) ConfigFunc {
 	}
 }
 
+// RetryIf is a configuration option that sets a predicate consulted after every failed
+// attempt. If it returns false, the retry sequence aborts immediately, as if the error
+// had been wrapped in UnrecoverableError. This lets callers retry only on certain errors,
+// e.g. a net.Error whose Temporary() is true.
+func RetryIf(f RetryIfFunc) ConfigFunc {
+	return func(b *backoffConfig) error {
+		b.retryIf = f
+		return nil
+	}
+}
+
+// AbortIf is a configuration option that sets a predicate consulted after every failed
+// attempt. It is the complement of RetryIf: if it returns true, the retry sequence
+// aborts immediately. This is convenient for classifying errors that should never be
+// retried, e.g. an HTTP 4xx response, while still retrying everything else.
+func AbortIf(f RetryIfFunc) ConfigFunc {
+	return func(b *backoffConfig) error {
+		b.abortIf = f
+		return nil
+	}
+}
+
+// OnRetry is a configuration option that sets a function called after every failed
+// attempt, including ones that go on to abort the retry sequence. Unlike Callback, which
+// only fires on eventual success, OnRetry lets callers observe every failure for metrics
+// or tracing purposes without hijacking the logger.
+func OnRetry(f OnRetryFunc) ConfigFunc {
+	return func(b *backoffConfig) error {
+		b.onRetry = f
+		return nil
+	}
+}
+
 // Jitter is a configuration option that sets the callback function for the backoff function.
 func Jitter(f JitterFunc) ConfigFunc {
 	return func(b *backoffConfig) error {
@@ -245,8 +488,107 @@ func Jitter(f JitterFunc) ConfigFunc {
 	}
 }
 
+// BaseDelay is a configuration option that sets the starting delay used by the
+// CappedExponential and DecorrelatedJitter strategies. It defaults to RetryAfter
+// (scaled by TimeScale) when unset.
+func BaseDelay(d time.Duration) ConfigFunc {
+	return func(b *backoffConfig) error {
+		b.baseDelay = d
+		return nil
+	}
+}
+
+// MaxDelay is a configuration option that caps the delay returned by the
+// CappedExponential and DecorrelatedJitter strategies. A zero value, the default,
+// means no cap is applied.
+func MaxDelay(d time.Duration) ConfigFunc {
+	return func(b *backoffConfig) error {
+		b.maxDelay = d
+		return nil
+	}
+}
+
+// Multiplier is a configuration option that sets the growth factor used by the
+// CappedExponential strategy. It defaults to 2.
+func Multiplier(f float64) ConfigFunc {
+	return func(b *backoffConfig) error {
+		b.multiplier = f
+		return nil
+	}
+}
+
+// cappedExponentialStrategy grows the delay exponentially with the attempt number,
+// base*multiplier^(attempt-1), capped at maxDelay once set.
+type cappedExponentialStrategy struct{ cfg *backoffConfig }
+
+func (s *cappedExponentialStrategy) NextDelay(attempt uint, prev time.Duration) time.Duration {
+	delay := time.Duration(float64(s.cfg.effectiveBaseDelay()) * math.Pow(s.cfg.effectiveMultiplier(), float64(attempt-1)))
+	if cap := s.cfg.maxDelay; cap > 0 && delay > cap {
+		delay = cap
+	}
+	return delay
+}
+
+// CappedExponential is a configuration option that selects the capped exponential
+// backoff shape, shaped by BaseDelay, MaxDelay and Multiplier, in place of the legacy
+// Jitter-based shape.
+func CappedExponential() ConfigFunc {
+	return func(b *backoffConfig) error {
+		b.strategy = &cappedExponentialStrategy{cfg: b}
+		return nil
+	}
+}
+
+// decorrelatedJitterStrategy picks each delay uniformly from [base, prev*3), capped at
+// maxDelay once set, with prev seeded from base on the first attempt. Basing the delay
+// on the previous one rather than the attempt count spreads out retries from competing
+// callers that started in lockstep, without the delay growing unbounded.
+type decorrelatedJitterStrategy struct{ cfg *backoffConfig }
+
+func (s *decorrelatedJitterStrategy) NextDelay(attempt uint, prev time.Duration) time.Duration {
+	base := s.cfg.effectiveBaseDelay()
+	if prev <= 0 {
+		prev = base
+	}
+
+	upper := prev * 3
+	delay := base
+	if upper > base {
+		delay = base + time.Duration(rand.Int63n(int64(upper-base)))
+	}
+
+	if cap := s.cfg.maxDelay; cap > 0 && delay > cap {
+		delay = cap
+	}
+	return delay
+}
+
+// DecorrelatedJitter is a configuration option that selects the decorrelated jitter
+// backoff shape, shaped by BaseDelay and MaxDelay, in place of the legacy Jitter-based
+// shape.
+func DecorrelatedJitter() ConfigFunc {
+	return func(b *backoffConfig) error {
+		b.strategy = &decorrelatedJitterStrategy{cfg: b}
+		return nil
+	}
+}
+
+func (b *backoffConfig) effectiveBaseDelay() time.Duration {
+	if b.baseDelay > 0 {
+		return b.baseDelay
+	}
+	return time.Duration(b.retryAfter) * b.timeScale
+}
+
+func (b *backoffConfig) effectiveMultiplier() float64 {
+	if b.multiplier > 0 {
+		return b.multiplier
+	}
+	return 2
+}
+
 // Credit: https://play.golang.org/p/Dyj99EjRVm
-func getLabel(f Func) string {
+func getLabel(f interface{}) string {
 	var label string
 	v := reflect.ValueOf(f)
 	if v.Kind() == reflect.Func {
@@ -265,30 +607,82 @@ func getLabel(f Func) string {
 func exec(f Func, b *backoffConfig) (result interface{}, err error) {
 	var prevErr error
 	var i uint
+	var prevDelay time.Duration
+	start := time.Now()
 	for i = 1; i <= b.maxRetries; i++ {
-		var d uint
 		b.invocations = i
 
-		if b.exponential {
-			d = uint(b.jitterFunc(b.retryAfter) * int(i))
-		} else {
-			d = uint(b.jitterFunc(b.retryAfter))
+		if b.circuitBreaker != nil && !b.circuitBreaker.Allow() {
+			return result, ErrCircuitOpen
+		}
+
+		d := b.nextDelay(i, prevDelay)
+		prevDelay = d
+
+		if b.ctx.Err() != nil {
+			if prevErr != nil {
+				return result, prevErr
+			}
+			return result, b.ctx.Err()
+		}
+
+		if b.maxElapsedTime > 0 && time.Since(start) > b.maxElapsedTime {
+			return result, fmt.Errorf("%w: %v", ErrElapsedTimeExceeded, prevErr)
+		}
+
+		select {
+		case <-b.ctx.Done():
+			if prevErr != nil {
+				return result, prevErr
+			}
+			return result, b.ctx.Err()
+		case <-time.After(d):
+		}
+
+		if b.maxElapsedTime > 0 && time.Since(start) > b.maxElapsedTime {
+			return result, fmt.Errorf("%w: %v", ErrElapsedTimeExceeded, prevErr)
+		}
+
+		if b.bulkhead != nil {
+			select {
+			case b.bulkhead <- struct{}{}:
+			case <-b.ctx.Done():
+				if prevErr != nil {
+					return result, prevErr
+				}
+				return result, b.ctx.Err()
+			}
+		}
+
+		result, err = b.invoke()
+
+		if b.bulkhead != nil {
+			<-b.bulkhead
+		}
+
+		if b.circuitBreaker != nil {
+			if err != nil {
+				b.circuitBreaker.RecordFailure()
+			} else {
+				b.circuitBreaker.RecordSuccess()
+			}
 		}
 
-		time.Sleep(time.Duration(d) * b.timeScale)
-		result, err = b.backoffFunc()
 		if err != nil {
-			switch err.(type) {
-			case *UnrecoverableError, UnrecoverableError:
-				return int64(0), err
-
-			default:
-				b.log.Warnf("%v (Attempt #%v): %v", b.label, i, err)
-				b.failedInvocations++
-				prevErr = err
-				err = nil
-				continue
+			if b.onRetry != nil {
+				b.onRetry(i, err)
+			}
+
+			var unrecoverable *UnrecoverableError
+			if errors.As(err, &unrecoverable) || !b.shouldRetry(err, i) {
+				return result, err
 			}
+
+			b.log.Warnf("%v (Attempt #%v): %v", b.label, i, err)
+			b.failedInvocations++
+			prevErr = err
+			err = nil
+			continue
 		}
 		prevErr = nil
 		break
@@ -299,36 +693,160 @@ func exec(f Func, b *backoffConfig) (result interface{}, err error) {
 	return result, prevErr
 }
 
+// nextDelay computes the wait before attempt, given the delay used before the previous
+// attempt (zero before the first attempt). When a Strategy has been configured via
+// CappedExponential or DecorrelatedJitter, it is consulted; otherwise the legacy
+// JitterFunc-based linear/exponential shape is used.
+func (b *backoffConfig) nextDelay(attempt uint, prevDelay time.Duration) time.Duration {
+	if b.strategy != nil {
+		return b.strategy.NextDelay(attempt, prevDelay)
+	}
+
+	var d uint
+	if b.exponential {
+		d = uint(b.jitterFunc(b.retryAfter) * int(attempt))
+	} else {
+		d = uint(b.jitterFunc(b.retryAfter))
+	}
+	return time.Duration(d) * b.timeScale
+}
+
+// shouldRetry consults the AbortIf/RetryIf predicates, if set, to decide whether the
+// retry sequence should continue after err. It defaults to true when neither is set.
+func (b *backoffConfig) shouldRetry(err error, attempt uint) bool {
+	if b.abortIf != nil && b.abortIf(err, attempt) {
+		return false
+	}
+	if b.retryIf != nil && !b.retryIf(err, attempt) {
+		return false
+	}
+	return true
+}
+
+// invoke runs the configured backoff function for a single attempt, honoring
+// perAttemptTimeout if one was set via PerAttemptTimeout. For a FuncCtx (LinearCtx,
+// ExponentialCtx), the timeout is applied by deriving a context.WithTimeout from b.ctx;
+// for a plain Func, which takes no context, the function is run in a goroutine and the
+// attempt is abandoned (but not killed) once the timeout elapses.
+func (b *backoffConfig) invoke() (interface{}, error) {
+	if b.backoffCtxFunc != nil {
+		ctx := b.ctx
+		if b.perAttemptTimeout > 0 {
+			var cancel context.CancelFunc
+			ctx, cancel = context.WithTimeout(ctx, b.perAttemptTimeout)
+			defer cancel()
+		}
+		return b.backoffCtxFunc(ctx)
+	}
+
+	if b.perAttemptTimeout <= 0 {
+		return b.backoffFunc()
+	}
+
+	type attemptResult struct {
+		result interface{}
+		err    error
+	}
+	ch := make(chan attemptResult, 1)
+	go func() {
+		result, err := b.backoffFunc()
+		ch <- attemptResult{result, err}
+	}()
+
+	select {
+	case out := <-ch:
+		return out.result, out.err
+	case <-time.After(b.perAttemptTimeout):
+		return nil, fmt.Errorf("attempt timed out after %s: %w", b.perAttemptTimeout, context.DeadlineExceeded)
+	}
+}
+
+// panicWithElapsedOrCtxErr panics with prevErr if one has been observed, or with b.ctx's
+// error otherwise, mirroring how exec resolves a cancelled/expired context.
+func panicWithElapsedOrCtxErr(b *backoffConfig, prevErr error) {
+	if prevErr != nil {
+		panic(prevErr.Error())
+	}
+	panic(b.ctx.Err().Error())
+}
+
 func mustExec(f Func, b *backoffConfig) (result interface{}) {
 	var err error
+	var prevErr error
 	var i uint
+	var prevDelay time.Duration
+	start := time.Now()
 	for i = 1; i <= b.maxRetries; i++ {
-		var d uint
 		b.invocations = i
 
-		if b.exponential {
-			d = uint(b.jitterFunc(b.retryAfter) * int(i))
-		} else {
-			d = uint(b.jitterFunc(b.retryAfter))
+		if b.circuitBreaker != nil && !b.circuitBreaker.Allow() {
+			panic(ErrCircuitOpen.Error())
+		}
+
+		d := b.nextDelay(i, prevDelay)
+		prevDelay = d
+
+		if b.ctx.Err() != nil {
+			panicWithElapsedOrCtxErr(b, prevErr)
+		}
+
+		if b.maxElapsedTime > 0 && time.Since(start) > b.maxElapsedTime {
+			panic(fmt.Errorf("%w: %v", ErrElapsedTimeExceeded, prevErr).Error())
+		}
+
+		select {
+		case <-b.ctx.Done():
+			panicWithElapsedOrCtxErr(b, prevErr)
+		case <-time.After(d):
+		}
+
+		if b.maxElapsedTime > 0 && time.Since(start) > b.maxElapsedTime {
+			panic(fmt.Errorf("%w: %v", ErrElapsedTimeExceeded, prevErr).Error())
+		}
+
+		if b.bulkhead != nil {
+			select {
+			case b.bulkhead <- struct{}{}:
+			case <-b.ctx.Done():
+				panicWithElapsedOrCtxErr(b, prevErr)
+			}
+		}
+
+		result, err = b.invoke()
+
+		if b.bulkhead != nil {
+			<-b.bulkhead
+		}
+
+		if b.circuitBreaker != nil {
+			if err != nil {
+				b.circuitBreaker.RecordFailure()
+			} else {
+				b.circuitBreaker.RecordSuccess()
+			}
 		}
 
-		time.Sleep(time.Duration(d) * b.timeScale)
-		result, err = b.backoffFunc()
 		if err != nil {
-			switch err.(type) {
-			case *UnrecoverableError, UnrecoverableError:
-				panic(fmt.Sprintf("giving up after %d tries", b.maxRetries))
+			if b.onRetry != nil {
+				b.onRetry(i, err)
+			}
 
-			default:
-				b.log.Warnf("%v (Attempt #%v): %v", b.label, i, err)
-				b.failedInvocations++
-				continue
+			var unrecoverable *UnrecoverableError
+			if errors.As(err, &unrecoverable) || !b.shouldRetry(err, i) {
+				panic(fmt.Sprintf("giving up after %d tries", b.maxRetries))
 			}
+
+			b.log.Warnf("%v (Attempt #%v): %v", b.label, i, err)
+			b.failedInvocations++
+			prevErr = err
+			err = nil
+			continue
 		}
+		prevErr = nil
 		break
 	}
 
-	if err != nil {
+	if prevErr != nil {
 		panic(fmt.Sprintf("giving up after %d tries", b.maxRetries))
 	}
 
@@ -1,6 +1,7 @@
 package backoff
 
 import (
+	"context"
 	"errors"
 	"fmt"
 	"io/ioutil"
@@ -258,3 +259,460 @@ func TestCallback(t *testing.T) {
 		t.Errorf("Expected error to be nil but found %v", err)
 	}
 }
+
+func successAfterCtxFunc(n int) (f func(ctx context.Context) (interface{}, error)) {
+	var i int
+	return func(ctx context.Context) (result interface{}, err error) {
+		if i < n {
+			i++
+			return nil, errSample
+		}
+		return "Success", nil
+	}
+}
+
+func TestSuccessLinearCtx(t *testing.T) {
+	result, err := LinearCtx(
+		context.Background(),
+		successAfterCtxFunc(2),
+		Retries(5),
+		TimeScale(time.Nanosecond),
+		Log(ioutil.Discard),
+	)
+
+	if result != "Success" {
+		t.Errorf("Expected result to be \"Success\" but found %v", result)
+	}
+
+	if err != nil {
+		t.Errorf("Expected error to be nil but found %v", err)
+	}
+}
+
+func TestCancelledLinearCtx(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	result, err := LinearCtx(
+		ctx,
+		func(ctx context.Context) (interface{}, error) { return successFunc() },
+		Retries(5),
+		TimeScale(time.Nanosecond),
+		Log(ioutil.Discard),
+	)
+
+	if result != nil {
+		t.Errorf("Expected result to be nil but found %v", result)
+	}
+
+	if err != context.Canceled {
+		t.Errorf("Expected error to be context.Canceled but found %v", err)
+	}
+}
+
+func TestSuccessExponentialCtx(t *testing.T) {
+	result, err := ExponentialCtx(
+		context.Background(),
+		successAfterCtxFunc(2),
+		Retries(5),
+		TimeScale(time.Nanosecond),
+		Log(ioutil.Discard),
+	)
+
+	if result != "Success" {
+		t.Errorf("Expected result to be \"Success\" but found %v", result)
+	}
+
+	if err != nil {
+		t.Errorf("Expected error to be nil but found %v", err)
+	}
+}
+
+func successAfterIntFunc(n int) func() (int, error) {
+	var i int
+	return func() (int, error) {
+		if i < n {
+			i++
+			return 0, errSample
+		}
+		return 42, nil
+	}
+}
+
+func TestSuccessLinearT(t *testing.T) {
+	result, err := LinearT(
+		successAfterIntFunc(2),
+		Retries(5),
+		TimeScale(time.Nanosecond),
+		Log(ioutil.Discard),
+	)
+
+	if result != 42 {
+		t.Errorf("Expected result to be 42 but found %v", result)
+	}
+
+	if err != nil {
+		t.Errorf("Expected error to be nil but found %v", err)
+	}
+}
+
+func TestFailingExponentialT(t *testing.T) {
+	result, err := ExponentialT(
+		func() (int, error) { return 0, errSample },
+		Retries(3),
+		TimeScale(time.Nanosecond),
+		Log(ioutil.Discard),
+	)
+
+	if result != 0 {
+		t.Errorf("Expected result to be 0 but found %v", result)
+	}
+
+	if err == nil {
+		t.Errorf("Expected an error but found nil")
+	}
+}
+
+func TestSuccessMustLinearT(t *testing.T) {
+	defer func() {
+		if r := recover(); r != nil {
+			t.Errorf("Expected MustLinearT not to panic but it did")
+		}
+	}()
+
+	result := MustLinearT(
+		successAfterIntFunc(1),
+		Retries(5),
+		TimeScale(time.Nanosecond),
+		Log(ioutil.Discard),
+	)
+
+	if result != 42 {
+		t.Errorf("Expected result to be 42 but found %v", result)
+	}
+}
+
+func TestFailingMustExponentialT(t *testing.T) {
+	defer func() {
+		if r := recover(); r == nil {
+			t.Errorf("Expected MustExponentialT to panic but it didn't")
+		}
+	}()
+
+	MustExponentialT(
+		func() (int, error) { return 0, errSample },
+		Retries(3),
+		TimeScale(time.Nanosecond),
+		Log(ioutil.Discard),
+	)
+}
+
+func TestCallbackT(t *testing.T) {
+	var seen int
+
+	result, err := LinearT(
+		successAfterIntFunc(1),
+		Retries(5),
+		TimeScale(time.Nanosecond),
+		Log(ioutil.Discard),
+		CallbackT(func(b *backoffConfig, r int) { seen = r }),
+	)
+
+	if result != 42 || err != nil {
+		t.Errorf("Expected result 42 and nil error but found %v, %v", result, err)
+	}
+
+	if seen != 42 {
+		t.Errorf("Expected CallbackT to observe 42 but found %v", seen)
+	}
+}
+
+func TestRetryIfAbortsImmediately(t *testing.T) {
+	var attempts int
+	f := func() (interface{}, error) {
+		attempts++
+		return nil, errSample
+	}
+
+	result, err := Linear(
+		f,
+		Retries(5),
+		TimeScale(time.Nanosecond),
+		Log(ioutil.Discard),
+		RetryIf(func(err error, attempt uint) bool { return false }),
+	)
+
+	if err != errSample {
+		t.Errorf("Expected error to be errSample but found %v", err)
+	}
+
+	if result != nil {
+		t.Errorf("Expected result to be nil but found %v", result)
+	}
+
+	if attempts != 1 {
+		t.Errorf("Expected exactly 1 attempt but found %v", attempts)
+	}
+}
+
+func TestAbortIfAbortsImmediately(t *testing.T) {
+	var attempts int
+	f := func() (interface{}, error) {
+		attempts++
+		return nil, errSample
+	}
+
+	result, err := Linear(
+		f,
+		Retries(5),
+		TimeScale(time.Nanosecond),
+		Log(ioutil.Discard),
+		AbortIf(func(err error, attempt uint) bool { return true }),
+	)
+
+	if err != errSample {
+		t.Errorf("Expected error to be errSample but found %v", err)
+	}
+
+	if result != nil {
+		t.Errorf("Expected result to be nil but found %v", result)
+	}
+
+	if attempts != 1 {
+		t.Errorf("Expected exactly 1 attempt but found %v", attempts)
+	}
+}
+
+func TestOnRetryObservesEveryFailure(t *testing.T) {
+	var seen []uint
+
+	result, err := Linear(
+		successAfterFunc(2),
+		Retries(5),
+		TimeScale(time.Nanosecond),
+		Log(ioutil.Discard),
+		OnRetry(func(attempt uint, err error) { seen = append(seen, attempt) }),
+	)
+
+	if result != "Success" {
+		t.Errorf("Expected result to be \"Success\" but found %v", result)
+	}
+
+	if err != nil {
+		t.Errorf("Expected error to be nil but found %v", err)
+	}
+
+	if len(seen) != 2 {
+		t.Errorf("Expected OnRetry to be called twice but found %v", seen)
+	}
+}
+
+func TestOnRetryObservesAbortingFailure(t *testing.T) {
+	var seen []uint
+
+	_, err := Linear(
+		failingFunc,
+		Retries(5),
+		TimeScale(time.Nanosecond),
+		Log(ioutil.Discard),
+		RetryIf(func(err error, attempt uint) bool { return false }),
+		OnRetry(func(attempt uint, err error) { seen = append(seen, attempt) }),
+	)
+
+	if err != errSample {
+		t.Errorf("Expected error to be errSample but found %v", err)
+	}
+
+	if len(seen) != 1 {
+		t.Errorf("Expected OnRetry to be called once for the aborting failure but found %v", seen)
+	}
+}
+
+func TestOnRetryObservesAbortingFailureMustLinear(t *testing.T) {
+	var seen []uint
+
+	defer func() {
+		recover()
+		if len(seen) != 1 {
+			t.Errorf("Expected OnRetry to be called once for the aborting failure but found %v", seen)
+		}
+	}()
+
+	MustLinear(
+		failingFunc,
+		Retries(5),
+		TimeScale(time.Nanosecond),
+		Log(ioutil.Discard),
+		RetryIf(func(err error, attempt uint) bool { return false }),
+		OnRetry(func(attempt uint, err error) { seen = append(seen, attempt) }),
+	)
+}
+
+func TestCappedExponentialStrategy(t *testing.T) {
+	var attempts int
+	f := func() (interface{}, error) {
+		attempts++
+		if attempts < 3 {
+			return nil, errSample
+		}
+		return "Success", nil
+	}
+
+	result, err := Linear(
+		f,
+		Retries(5),
+		TimeScale(time.Nanosecond),
+		BaseDelay(time.Nanosecond),
+		MaxDelay(10*time.Nanosecond),
+		Multiplier(2),
+		CappedExponential(),
+		Log(ioutil.Discard),
+	)
+
+	if result != "Success" {
+		t.Errorf("Expected result to be \"Success\" but found %v", result)
+	}
+
+	if err != nil {
+		t.Errorf("Expected error to be nil but found %v", err)
+	}
+}
+
+func TestCappedExponentialStrategyRespectsCap(t *testing.T) {
+	s := &cappedExponentialStrategy{cfg: &backoffConfig{baseDelay: time.Millisecond, maxDelay: 5 * time.Millisecond, multiplier: 2}}
+
+	if d := s.NextDelay(10, 0); d != 5*time.Millisecond {
+		t.Errorf("Expected delay to be capped at 5ms but found %v", d)
+	}
+}
+
+func TestDecorrelatedJitterStrategy(t *testing.T) {
+	result, err := Exponential(
+		failingFunc,
+		Retries(3),
+		TimeScale(time.Nanosecond),
+		BaseDelay(time.Nanosecond),
+		MaxDelay(100*time.Nanosecond),
+		DecorrelatedJitter(),
+		Log(ioutil.Discard),
+	)
+
+	if result != nil {
+		t.Errorf("Expected result to be nil but found %v", result)
+	}
+
+	if err == nil {
+		t.Errorf("Expected error but found nil")
+	}
+}
+
+func TestDecorrelatedJitterStrategyRespectsCap(t *testing.T) {
+	s := &decorrelatedJitterStrategy{cfg: &backoffConfig{baseDelay: time.Millisecond, maxDelay: 2 * time.Millisecond}}
+
+	for i := 0; i < 50; i++ {
+		d := s.NextDelay(uint(i+1), 10*time.Millisecond)
+		if d > 2*time.Millisecond {
+			t.Fatalf("Expected delay to be capped at 2ms but found %v", d)
+		}
+	}
+}
+
+func TestCancelledMustLinear(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	defer func() {
+		r := recover()
+		if r == nil {
+			t.Fatalf("Expected MustLinear to panic when its context is already cancelled")
+		}
+	}()
+
+	MustLinear(
+		successFunc,
+		Retries(5),
+		TimeScale(time.Nanosecond),
+		Context(ctx),
+		Log(ioutil.Discard),
+	)
+}
+
+func TestMaxElapsedTimeMustLinear(t *testing.T) {
+	defer func() {
+		r := recover()
+		if r == nil {
+			t.Fatalf("Expected MustLinear to panic once MaxElapsedTime is exceeded")
+		}
+	}()
+
+	MustLinear(
+		failingFunc,
+		Retries(100),
+		TimeScale(time.Nanosecond),
+		MaxElapsedTime(5*time.Millisecond),
+		Log(ioutil.Discard),
+	)
+}
+
+func TestMaxElapsedTimeLinear(t *testing.T) {
+	result, err := Linear(
+		failingFunc,
+		Retries(100),
+		TimeScale(time.Nanosecond),
+		MaxElapsedTime(5*time.Millisecond),
+		Log(ioutil.Discard),
+	)
+
+	if result != nil {
+		t.Errorf("Expected result to be nil but found %v", result)
+	}
+
+	if !errors.Is(err, ErrElapsedTimeExceeded) {
+		t.Errorf("Expected error to wrap ErrElapsedTimeExceeded but found %v", err)
+	}
+}
+
+func TestPerAttemptTimeoutLinear(t *testing.T) {
+	slowFunc := func() (interface{}, error) {
+		time.Sleep(50 * time.Millisecond)
+		return "too slow", nil
+	}
+
+	result, err := Linear(
+		slowFunc,
+		Retries(1),
+		TimeScale(time.Nanosecond),
+		PerAttemptTimeout(time.Millisecond),
+		Log(ioutil.Discard),
+	)
+
+	if result != nil {
+		t.Errorf("Expected result to be nil but found %v", result)
+	}
+
+	if !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("Expected error to wrap context.DeadlineExceeded but found %v", err)
+	}
+}
+
+func TestDeadlineExceededExponentialCtx(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), time.Nanosecond)
+	defer cancel()
+	time.Sleep(time.Millisecond)
+
+	result, err := ExponentialCtx(
+		ctx,
+		func(ctx context.Context) (interface{}, error) { return nil, errSample },
+		Retries(5),
+		TimeScale(time.Nanosecond),
+		Log(ioutil.Discard),
+	)
+
+	if result != nil {
+		t.Errorf("Expected result to be nil but found %v", result)
+	}
+
+	if err != context.DeadlineExceeded {
+		t.Errorf("Expected error to be context.DeadlineExceeded but found %v", err)
+	}
+}
@@ -0,0 +1,218 @@
+package backoff
+
+import (
+	"container/ring"
+	"errors"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// ErrCircuitOpen is returned by exec when a CircuitBreaker configured via
+// WithCircuitBreaker is open, short-circuiting the attempt without sleeping or calling
+// the wrapped function.
+var ErrCircuitOpen = errors.New("backoff: circuit breaker is open")
+
+// CircuitBreakerState is the state of a CircuitBreaker.
+type CircuitBreakerState int32
+
+const (
+	// StateClosed lets attempts through normally.
+	StateClosed CircuitBreakerState = iota
+	// StateOpen rejects every attempt immediately with ErrCircuitOpen.
+	StateOpen
+	// StateHalfOpen lets a single probe attempt through to decide whether to close the
+	// circuit again.
+	StateHalfOpen
+)
+
+// String implements fmt.Stringer.
+func (s CircuitBreakerState) String() string {
+	switch s {
+	case StateClosed:
+		return "closed"
+	case StateOpen:
+		return "open"
+	case StateHalfOpen:
+		return "half-open"
+	default:
+		return "unknown"
+	}
+}
+
+type outcome struct {
+	at      time.Time
+	success bool
+}
+
+// CircuitBreaker tracks a rolling failure ratio over a time window and, once the ratio
+// crosses a threshold, trips Open so exec can short-circuit attempts instead of sleeping
+// and calling the wrapped function. After openDuration it transitions to HalfOpen and
+// lets a single probe attempt through: success closes the circuit again, failure reopens
+// it.
+//
+// A CircuitBreaker is safe for concurrent use, and is typically shared across several
+// Linear/Exponential calls (e.g. one per downstream dependency) via WithCircuitBreaker.
+type CircuitBreaker struct {
+	mu sync.Mutex
+
+	window       time.Duration
+	threshold    float64
+	minSamples   int
+	openDuration time.Duration
+
+	state         int32 // atomic CircuitBreakerState
+	openedAt      time.Time
+	probeInFlight bool
+	outcomes      *ring.Ring
+}
+
+// NewCircuitBreaker creates a CircuitBreaker that trips Open once at least minSamples
+// outcomes have landed within window and the failure ratio among them reaches
+// threshold (0 to 1). Once Open, it stays Open for openDuration before allowing a
+// single HalfOpen probe attempt.
+func NewCircuitBreaker(window time.Duration, threshold float64, minSamples int, openDuration time.Duration) *CircuitBreaker {
+	capacity := minSamples * 4
+	if capacity < 16 {
+		capacity = 16
+	}
+	return &CircuitBreaker{
+		window:       window,
+		threshold:    threshold,
+		minSamples:   minSamples,
+		openDuration: openDuration,
+		outcomes:     ring.New(capacity),
+	}
+}
+
+// Allow reports whether an attempt may proceed, transitioning Open to HalfOpen once
+// openDuration has elapsed and admitting exactly one probe attempt while HalfOpen.
+func (cb *CircuitBreaker) Allow() bool {
+	cb.mu.Lock()
+	defer cb.mu.Unlock()
+
+	switch CircuitBreakerState(atomic.LoadInt32(&cb.state)) {
+	case StateOpen:
+		if time.Since(cb.openedAt) < cb.openDuration {
+			return false
+		}
+		atomic.StoreInt32(&cb.state, int32(StateHalfOpen))
+		cb.probeInFlight = true
+		return true
+	case StateHalfOpen:
+		if cb.probeInFlight {
+			return false
+		}
+		cb.probeInFlight = true
+		return true
+	default:
+		return true
+	}
+}
+
+// RecordSuccess reports that an allowed attempt succeeded.
+func (cb *CircuitBreaker) RecordSuccess() {
+	cb.record(true)
+}
+
+// RecordFailure reports that an allowed attempt failed.
+func (cb *CircuitBreaker) RecordFailure() {
+	cb.record(false)
+}
+
+func (cb *CircuitBreaker) record(success bool) {
+	cb.mu.Lock()
+	defer cb.mu.Unlock()
+
+	if CircuitBreakerState(atomic.LoadInt32(&cb.state)) == StateHalfOpen {
+		cb.probeInFlight = false
+		if success {
+			cb.outcomes = ring.New(cb.outcomes.Len())
+			atomic.StoreInt32(&cb.state, int32(StateClosed))
+		} else {
+			cb.trip()
+		}
+		return
+	}
+
+	cb.outcomes.Value = outcome{at: time.Now(), success: success}
+	cb.outcomes = cb.outcomes.Next()
+
+	failures, total := cb.windowStats()
+	if total >= cb.minSamples && float64(failures)/float64(total) >= cb.threshold {
+		cb.trip()
+	}
+}
+
+func (cb *CircuitBreaker) trip() {
+	atomic.StoreInt32(&cb.state, int32(StateOpen))
+	cb.openedAt = time.Now()
+	cb.probeInFlight = false
+}
+
+// windowStats counts failures and total outcomes recorded within window. Callers must
+// hold cb.mu.
+func (cb *CircuitBreaker) windowStats() (failures, total int) {
+	cutoff := time.Now().Add(-cb.window)
+	cb.outcomes.Do(func(v interface{}) {
+		o, ok := v.(outcome)
+		if !ok || o.at.Before(cutoff) {
+			return
+		}
+		total++
+		if !o.success {
+			failures++
+		}
+	})
+	return failures, total
+}
+
+// CircuitBreakerStats summarizes a CircuitBreaker's current state, for observability.
+type CircuitBreakerStats struct {
+	State        CircuitBreakerState
+	Samples      int
+	Failures     int
+	FailureRatio float64
+}
+
+// Stats returns a snapshot of the CircuitBreaker's current state and rolling failure
+// ratio.
+func (cb *CircuitBreaker) Stats() CircuitBreakerStats {
+	cb.mu.Lock()
+	defer cb.mu.Unlock()
+
+	failures, total := cb.windowStats()
+	var ratio float64
+	if total > 0 {
+		ratio = float64(failures) / float64(total)
+	}
+	return CircuitBreakerStats{
+		State:        CircuitBreakerState(atomic.LoadInt32(&cb.state)),
+		Samples:      total,
+		Failures:     failures,
+		FailureRatio: ratio,
+	}
+}
+
+// WithCircuitBreaker is a configuration option that wraps the retry loop with cb. While
+// cb is Open, exec short-circuits immediately with ErrCircuitOpen instead of sleeping
+// and calling the wrapped function.
+func WithCircuitBreaker(cb *CircuitBreaker) ConfigFunc {
+	return func(b *backoffConfig) error {
+		b.circuitBreaker = cb
+		return nil
+	}
+}
+
+// Bulkhead is a configuration option that bounds the number of attempts allowed to run
+// concurrently, using a buffered channel as a semaphore acquired before each attempt.
+// The semaphore is created once, when Bulkhead is called, so passing the same
+// ConfigFunc value to multiple Linear/Exponential calls shares the bound across their
+// goroutines.
+func Bulkhead(maxConcurrent int) ConfigFunc {
+	sem := make(chan struct{}, maxConcurrent)
+	return func(b *backoffConfig) error {
+		b.bulkhead = sem
+		return nil
+	}
+}
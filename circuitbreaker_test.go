@@ -0,0 +1,126 @@
+package backoff
+
+import (
+	"errors"
+	"io/ioutil"
+	"testing"
+	"time"
+)
+
+func TestCircuitBreakerOpensAfterThreshold(t *testing.T) {
+	cb := NewCircuitBreaker(time.Minute, 0.5, 2, time.Minute)
+
+	if cb.Stats().State != StateClosed {
+		t.Fatalf("Expected a new CircuitBreaker to be closed")
+	}
+
+	cb.RecordFailure()
+	cb.RecordFailure()
+
+	if cb.Stats().State != StateOpen {
+		t.Errorf("Expected CircuitBreaker to be open after reaching the failure threshold")
+	}
+
+	if cb.Allow() {
+		t.Errorf("Expected Allow to reject attempts while open")
+	}
+}
+
+func TestCircuitBreakerHalfOpenProbeCloses(t *testing.T) {
+	cb := NewCircuitBreaker(time.Minute, 0.5, 2, time.Millisecond)
+	cb.RecordFailure()
+	cb.RecordFailure()
+
+	time.Sleep(5 * time.Millisecond)
+
+	if !cb.Allow() {
+		t.Fatalf("Expected Allow to admit a probe attempt once openDuration elapsed")
+	}
+	if cb.Stats().State != StateHalfOpen {
+		t.Fatalf("Expected CircuitBreaker to be half-open during the probe")
+	}
+
+	cb.RecordSuccess()
+
+	if cb.Stats().State != StateClosed {
+		t.Errorf("Expected a successful probe to close the circuit")
+	}
+}
+
+func TestCircuitBreakerHalfOpenProbeReopens(t *testing.T) {
+	cb := NewCircuitBreaker(time.Minute, 0.5, 2, time.Millisecond)
+	cb.RecordFailure()
+	cb.RecordFailure()
+
+	time.Sleep(5 * time.Millisecond)
+	cb.Allow()
+	cb.RecordFailure()
+
+	if cb.Stats().State != StateOpen {
+		t.Errorf("Expected a failed probe to reopen the circuit")
+	}
+}
+
+func TestWithCircuitBreakerShortCircuitsExec(t *testing.T) {
+	cb := NewCircuitBreaker(time.Minute, 0.5, 1, time.Minute)
+	cb.RecordFailure()
+
+	var attempts int
+	f := func() (interface{}, error) {
+		attempts++
+		return "Success", nil
+	}
+
+	result, err := Linear(
+		f,
+		Retries(5),
+		TimeScale(time.Nanosecond),
+		Log(ioutil.Discard),
+		WithCircuitBreaker(cb),
+	)
+
+	if result != nil {
+		t.Errorf("Expected result to be nil but found %v", result)
+	}
+
+	if !errors.Is(err, ErrCircuitOpen) {
+		t.Errorf("Expected error to be ErrCircuitOpen but found %v", err)
+	}
+
+	if attempts != 0 {
+		t.Errorf("Expected the wrapped function not to be called while the circuit is open")
+	}
+}
+
+func TestBulkheadBoundsConcurrency(t *testing.T) {
+	bulkhead := Bulkhead(1)
+
+	var running int32
+	var maxRunning int32
+	f := func() (interface{}, error) {
+		running++
+		if running > maxRunning {
+			maxRunning = running
+		}
+		time.Sleep(time.Millisecond)
+		running--
+		return "Success", nil
+	}
+
+	done := make(chan struct{})
+	go func() {
+		Linear(f, Retries(1), TimeScale(time.Nanosecond), Log(ioutil.Discard), bulkhead)
+		close(done)
+	}()
+
+	result, err := Linear(f, Retries(1), TimeScale(time.Nanosecond), Log(ioutil.Discard), bulkhead)
+	<-done
+
+	if result != "Success" || err != nil {
+		t.Errorf("Expected result \"Success\" and nil error but found %v, %v", result, err)
+	}
+
+	if maxRunning > 1 {
+		t.Errorf("Expected Bulkhead to bound concurrency to 1 but observed %v concurrent attempts", maxRunning)
+	}
+}
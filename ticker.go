@@ -0,0 +1,86 @@
+package backoff
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	log "github.com/sirupsen/logrus"
+)
+
+// Ticker emits ticks on C at the intervals dictated by the configured backoff shape
+// (the legacy Jitter-based linear shape by default, or a Strategy such as
+// CappedExponential/DecorrelatedJitter), for callers whose retriable work can't be
+// expressed as a single Func — e.g. a reconnect loop that must re-establish a
+// long-lived stream. It stops, closing C, after the configured maxRetries ticks, when
+// its Context is done, or when Stop is called.
+type Ticker struct {
+	// C delivers a tick at each computed backoff interval. It is closed when the
+	// Ticker stops.
+	C <-chan time.Time
+
+	c        chan time.Time
+	stop     chan struct{}
+	stopOnce sync.Once
+}
+
+// NewTicker creates a Ticker configured the same way as Linear/Exponential, via
+// Retries, RetryAfter, TimeScale, Jitter, BaseDelay, MaxDelay, Multiplier,
+// CappedExponential, DecorrelatedJitter and Context.
+func NewTicker(opts ...ConfigFunc) *Ticker {
+	cfg := &backoffConfig{
+		maxRetries:  10,
+		retryAfter:  500,
+		jitterFunc:  FullJitter,
+		exponential: false,
+		label:       "backoff.Ticker",
+		timeScale:   time.Millisecond,
+		log:         log.New(),
+		ctx:         context.Background(),
+	}
+
+	for _, opt := range opts {
+		if err := opt(cfg); err != nil {
+			cfg.log.Fatal(err)
+		}
+	}
+
+	t := &Ticker{
+		c:    make(chan time.Time, 1),
+		stop: make(chan struct{}),
+	}
+	t.C = t.c
+
+	go t.run(cfg)
+
+	return t
+}
+
+func (t *Ticker) run(cfg *backoffConfig) {
+	defer close(t.c)
+
+	var prevDelay time.Duration
+	for i := uint(1); i <= cfg.maxRetries; i++ {
+		d := cfg.nextDelay(i, prevDelay)
+		prevDelay = d
+
+		select {
+		case <-t.stop:
+			return
+		case <-cfg.ctx.Done():
+			return
+		case tick := <-time.After(d):
+			select {
+			case t.c <- tick:
+			case <-t.stop:
+				return
+			}
+		}
+	}
+}
+
+// Stop terminates the Ticker. It is safe to call more than once and from multiple
+// goroutines.
+func (t *Ticker) Stop() {
+	t.stopOnce.Do(func() { close(t.stop) })
+}
@@ -0,0 +1,42 @@
+package backoff
+
+import (
+	"testing"
+	"time"
+)
+
+func TestTickerEmitsAndStops(t *testing.T) {
+	ticker := NewTicker(
+		Retries(3),
+		TimeScale(time.Nanosecond),
+	)
+
+	var ticks int
+	for range ticker.C {
+		ticks++
+	}
+
+	if ticks != 3 {
+		t.Errorf("Expected 3 ticks but found %v", ticks)
+	}
+}
+
+func TestTickerStop(t *testing.T) {
+	ticker := NewTicker(
+		Retries(100),
+		TimeScale(time.Millisecond),
+	)
+
+	<-ticker.C
+	ticker.Stop()
+	ticker.Stop() // must be safe to call more than once
+
+	select {
+	case _, ok := <-ticker.C:
+		if ok {
+			t.Errorf("Expected no further ticks after Stop")
+		}
+	case <-time.After(50 * time.Millisecond):
+		t.Errorf("Expected C to be closed shortly after Stop")
+	}
+}